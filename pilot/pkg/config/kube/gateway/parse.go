@@ -0,0 +1,112 @@
+// Copyright 2020 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ParseTCPRouteRules reads the `spec.rules` of a `networking.x-k8s.io/v1alpha1`
+// TCPRoute object into the tcpRouteRule shape BuildRouteVirtualService
+// consumes.
+func ParseTCPRouteRules(obj *unstructured.Unstructured) ([]tcpRouteRule, error) {
+	rawRules, _, err := unstructured.NestedSlice(obj.Object, "spec", "rules")
+	if err != nil {
+		return nil, fmt.Errorf("reading TCPRoute %s spec.rules: %v", obj.GetName(), err)
+	}
+
+	rules := make([]tcpRouteRule, 0, len(rawRules))
+	for _, raw := range rawRules {
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("TCPRoute %s has a malformed rule", obj.GetName())
+		}
+		forwardTo, err := parseForwardTo(m["forwardTo"])
+		if err != nil {
+			return nil, fmt.Errorf("TCPRoute %s: %v", obj.GetName(), err)
+		}
+		rules = append(rules, tcpRouteRule{ForwardTo: forwardTo})
+	}
+	return rules, nil
+}
+
+// ParseTLSRouteRules reads the `spec.rules` of a `networking.x-k8s.io/v1alpha1`
+// TLSRoute object into the tlsRouteRule shape BuildRouteVirtualService
+// consumes.
+func ParseTLSRouteRules(obj *unstructured.Unstructured) ([]tlsRouteRule, error) {
+	rawRules, _, err := unstructured.NestedSlice(obj.Object, "spec", "rules")
+	if err != nil {
+		return nil, fmt.Errorf("reading TLSRoute %s spec.rules: %v", obj.GetName(), err)
+	}
+
+	rules := make([]tlsRouteRule, 0, len(rawRules))
+	for _, raw := range rawRules {
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("TLSRoute %s has a malformed rule", obj.GetName())
+		}
+		sniHosts, _, err := unstructured.NestedStringSlice(m, "match", "sniHosts")
+		if err != nil {
+			return nil, fmt.Errorf("TLSRoute %s: reading match.sniHosts: %v", obj.GetName(), err)
+		}
+		forwardTo, err := parseForwardTo(m["forwardTo"])
+		if err != nil {
+			return nil, fmt.Errorf("TLSRoute %s: %v", obj.GetName(), err)
+		}
+		rules = append(rules, tlsRouteRule{SNIHosts: sniHosts, ForwardTo: forwardTo})
+	}
+	return rules, nil
+}
+
+// parseForwardTo accepts either shape `forwardTo` is allowed to take in the
+// Gateway API CRDs: a single destination object, or a list of them (each
+// optionally weighted, as HTTPRoute's forwardTo already allows for canary
+// splits).
+func parseForwardTo(raw interface{}) ([]forwardToDestination, error) {
+	var entries []interface{}
+	switch v := raw.(type) {
+	case nil:
+		return nil, fmt.Errorf("rule has no forwardTo destinations")
+	case []interface{}:
+		entries = v
+	case map[string]interface{}:
+		entries = []interface{}{v}
+	default:
+		return nil, fmt.Errorf("forwardTo has an unexpected shape %T", raw)
+	}
+
+	dests := make([]forwardToDestination, 0, len(entries))
+	for _, e := range entries {
+		m, ok := e.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("forwardTo entry has an unexpected shape %T", e)
+		}
+		name, _, _ := unstructured.NestedString(m, "name")
+		if name == "" {
+			return nil, fmt.Errorf("forwardTo entry missing name")
+		}
+		dest := forwardToDestination{ServiceName: name, Port: 80}
+		if port, ok, _ := unstructured.NestedInt64(m, "port"); ok {
+			dest.Port = uint32(port)
+		}
+		if weight, ok, _ := unstructured.NestedInt64(m, "weight"); ok {
+			dest.Weight = int32(weight)
+		}
+		dests = append(dests, dest)
+	}
+	return dests, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2020 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func tcpRouteObj(name string, forwardTo interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": name},
+		"spec": map[string]interface{}{
+			"rules": []interface{}{
+				map[string]interface{}{"forwardTo": forwardTo},
+			},
+		},
+	}}
+}
+
+func TestParseTCPRouteRulesSingleForwardTo(t *testing.T) {
+	obj := tcpRouteObj("tcp-route", map[string]interface{}{
+		"group": "v1", "resource": "Service", "name": "tcp-server",
+	})
+	rules, err := ParseTCPRouteRules(obj)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rules) != 1 || len(rules[0].ForwardTo) != 1 {
+		t.Fatalf("got %+v, want 1 rule with 1 forwardTo", rules)
+	}
+	if got := rules[0].ForwardTo[0].ServiceName; got != "tcp-server" {
+		t.Errorf("ServiceName = %q, want tcp-server", got)
+	}
+}
+
+func TestParseTCPRouteRulesMissingForwardTo(t *testing.T) {
+	obj := tcpRouteObj("tcp-route", nil)
+	if _, err := ParseTCPRouteRules(obj); err == nil {
+		t.Fatal("expected an error for a rule with no forwardTo, got nil")
+	}
+}
+
+func TestParseTLSRouteRulesWeightedList(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "tls-route"},
+		"spec": map[string]interface{}{
+			"rules": []interface{}{
+				map[string]interface{}{
+					"match": map[string]interface{}{"sniHosts": []interface{}{"a.tls.example"}},
+					"forwardTo": []interface{}{
+						map[string]interface{}{"group": "v1", "resource": "Service", "name": "tls-server-a", "weight": int64(90)},
+						map[string]interface{}{"group": "v1", "resource": "Service", "name": "tls-server-b", "weight": int64(10)},
+					},
+				},
+			},
+		},
+	}}
+	rules, err := ParseTLSRouteRules(obj)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rules) != 1 || len(rules[0].SNIHosts) != 1 || rules[0].SNIHosts[0] != "a.tls.example" {
+		t.Fatalf("got %+v, want 1 rule matching a.tls.example", rules)
+	}
+	if len(rules[0].ForwardTo) != 2 {
+		t.Fatalf("got %d forwardTo destinations, want 2", len(rules[0].ForwardTo))
+	}
+	if got := rules[0].ForwardTo[1].Weight; got != 10 {
+		t.Errorf("second destination weight = %d, want 10", got)
+	}
+}
+
+func TestReconcileListenerRoutesTCP(t *testing.T) {
+	obj := tcpRouteObj("tcp-route", map[string]interface{}{
+		"group": "v1", "resource": "Service", "name": "tcp-server", "port": int64(9090),
+	})
+	tcpRoutes, tlsRoutes, err := ReconcileListenerRoutes(ProtocolTCP, 31400, []*unstructured.Unstructured{obj}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tlsRoutes != nil {
+		t.Fatalf("expected no TLSRoutes, got %v", tlsRoutes)
+	}
+	if len(tcpRoutes) != 1 {
+		t.Fatalf("expected 1 TCPRoute, got %d", len(tcpRoutes))
+	}
+	if got := tcpRoutes[0].Route[0].Destination.Port.Number; got != 9090 {
+		t.Errorf("destination port = %d, want 9090", got)
+	}
+}
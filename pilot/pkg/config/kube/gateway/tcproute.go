@@ -0,0 +1,99 @@
+// Copyright 2020 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"fmt"
+
+	"istio.io/api/networking/v1alpha3"
+)
+
+// buildTCPRouteVirtualService turns a `networking.x-k8s.io/v1alpha1` TCPRoute
+// bound to a `protocol: tcp` Gateway listener into the TCPRoute block of an
+// Istio VirtualService. Unlike HTTPRoute, matching is purely by destination
+// port: the listener's port selects which TCPRoute rule applies, since the
+// underlying protocol carries no host information to route on.
+func buildTCPRouteVirtualService(listenerPort uint32, rule tcpRouteRule) (*v1alpha3.TCPRoute, error) {
+	if len(rule.ForwardTo) == 0 {
+		return nil, fmt.Errorf("tcp route rule has no forwardTo destinations")
+	}
+
+	route := &v1alpha3.TCPRoute{
+		Match: []*v1alpha3.L4MatchAttributes{{
+			Port: listenerPort,
+		}},
+	}
+	for _, dst := range rule.ForwardTo {
+		route.Route = append(route.Route, &v1alpha3.RouteDestination{
+			Destination: &v1alpha3.Destination{
+				Host: dst.ServiceName,
+				Port: &v1alpha3.PortSelector{Number: dst.Port},
+			},
+			Weight: dst.Weight,
+		})
+	}
+	return route, nil
+}
+
+// buildTLSRouteVirtualService turns a `networking.x-k8s.io/v1alpha1` TLSRoute
+// bound to a `protocol: tls` Gateway listener into the TLSRoute block of an
+// Istio VirtualService, matching on SNI so multiple backends can share one
+// listener/port.
+func buildTLSRouteVirtualService(listenerPort uint32, rule tlsRouteRule) (*v1alpha3.TLSRoute, error) {
+	if len(rule.SNIHosts) == 0 {
+		return nil, fmt.Errorf("tls route rule has no sniHosts to match")
+	}
+	if len(rule.ForwardTo) == 0 {
+		return nil, fmt.Errorf("tls route rule has no forwardTo destinations")
+	}
+
+	route := &v1alpha3.TLSRoute{
+		Match: []*v1alpha3.TLSMatchAttributes{{
+			Port:     listenerPort,
+			SniHosts: rule.SNIHosts,
+		}},
+	}
+	for _, dst := range rule.ForwardTo {
+		route.Route = append(route.Route, &v1alpha3.RouteDestination{
+			Destination: &v1alpha3.Destination{
+				Host: dst.ServiceName,
+				Port: &v1alpha3.PortSelector{Number: dst.Port},
+			},
+			Weight: dst.Weight,
+		})
+	}
+	return route, nil
+}
+
+// forwardToDestination is the common shape of a TCPRoute/TLSRoute rule's
+// forwardTo entry: a backend Service, optionally weighted against siblings.
+type forwardToDestination struct {
+	ServiceName string
+	Port        uint32
+	Weight      int32
+}
+
+// tcpRouteRule is the subset of a TCPRoute's spec this translator consumes.
+type tcpRouteRule struct {
+	ForwardTo []forwardToDestination
+}
+
+// tlsRouteRule is the subset of a TLSRoute's spec this translator consumes.
+// SNIHosts is the rule's `match.sniHosts`, used to pick a listener's
+// TLSRoute for an incoming ClientHello.
+type tlsRouteRule struct {
+	SNIHosts  []string
+	ForwardTo []forwardToDestination
+}
@@ -0,0 +1,102 @@
+// Copyright 2020 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"istio.io/api/networking/v1alpha3"
+)
+
+// ListenerProtocol is a Gateway API Gateway listener's `spec.protocol` value,
+// restricted to the protocols this package knows how to translate.
+type ListenerProtocol string
+
+const (
+	// ProtocolTCP is the `protocol: tcp` listener value routed by TCPRoute.
+	ProtocolTCP ListenerProtocol = "tcp"
+
+	// ProtocolTLS is the `protocol: tls` listener value routed by TLSRoute.
+	ProtocolTLS ListenerProtocol = "tls"
+)
+
+// BuildRouteVirtualService translates the TCPRoute/TLSRoute rules bound to a
+// single Gateway listener into the VirtualService TCPRoute/TLSRoute blocks
+// that configure the mesh's proxies. ReconcileListenerRoutes calls it after
+// parsing the bound route objects.
+func BuildRouteVirtualService(
+	listenerProtocol ListenerProtocol,
+	listenerPort uint32,
+	tcpRules []tcpRouteRule,
+	tlsRules []tlsRouteRule,
+) (tcpRoutes []*v1alpha3.TCPRoute, tlsRoutes []*v1alpha3.TLSRoute, err error) {
+	switch listenerProtocol {
+	case ProtocolTCP:
+		for _, rule := range tcpRules {
+			route, err := buildTCPRouteVirtualService(listenerPort, rule)
+			if err != nil {
+				return nil, nil, err
+			}
+			tcpRoutes = append(tcpRoutes, route)
+		}
+		return tcpRoutes, nil, nil
+	case ProtocolTLS:
+		for _, rule := range tlsRules {
+			route, err := buildTLSRouteVirtualService(listenerPort, rule)
+			if err != nil {
+				return nil, nil, err
+			}
+			tlsRoutes = append(tlsRoutes, route)
+		}
+		return nil, tlsRoutes, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported listener protocol %q for TCPRoute/TLSRoute translation", listenerProtocol)
+	}
+}
+
+// ReconcileListenerRoutes handles a single `protocol: tcp`/`protocol: tls`
+// Gateway listener: given the TCPRoute/TLSRoute objects bound to that
+// listener, it parses their specs and translates them into the listener's
+// VirtualService TCPRoute/TLSRoute blocks. Nothing in this package watches
+// Gateway, TCPRoute or TLSRoute objects or calls this function; the caller
+// supplies the bound objects.
+func ReconcileListenerRoutes(
+	listenerProtocol ListenerProtocol,
+	listenerPort uint32,
+	tcpRouteObjs []*unstructured.Unstructured,
+	tlsRouteObjs []*unstructured.Unstructured,
+) (tcpRoutes []*v1alpha3.TCPRoute, tlsRoutes []*v1alpha3.TLSRoute, err error) {
+	var tcpRules []tcpRouteRule
+	for _, obj := range tcpRouteObjs {
+		rules, err := ParseTCPRouteRules(obj)
+		if err != nil {
+			return nil, nil, err
+		}
+		tcpRules = append(tcpRules, rules...)
+	}
+
+	var tlsRules []tlsRouteRule
+	for _, obj := range tlsRouteObjs {
+		rules, err := ParseTLSRouteRules(obj)
+		if err != nil {
+			return nil, nil, err
+		}
+		tlsRules = append(tlsRules, rules...)
+	}
+
+	return BuildRouteVirtualService(listenerProtocol, listenerPort, tcpRules, tlsRules)
+}
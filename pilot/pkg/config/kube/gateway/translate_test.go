@@ -0,0 +1,69 @@
+// Copyright 2020 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import "testing"
+
+func TestBuildRouteVirtualServiceTCP(t *testing.T) {
+	tcpRoutes, tlsRoutes, err := BuildRouteVirtualService(ProtocolTCP, 31400, []tcpRouteRule{
+		{ForwardTo: []forwardToDestination{{ServiceName: "tcp-server", Port: 9090}}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tlsRoutes != nil {
+		t.Fatalf("expected no TLSRoutes for a tcp listener, got %v", tlsRoutes)
+	}
+	if len(tcpRoutes) != 1 {
+		t.Fatalf("expected 1 TCPRoute, got %d", len(tcpRoutes))
+	}
+	if got := tcpRoutes[0].Match[0].Port; got != 31400 {
+		t.Errorf("match port = %d, want 31400", got)
+	}
+	if got := tcpRoutes[0].Route[0].Destination.Host; got != "tcp-server" {
+		t.Errorf("destination host = %q, want tcp-server", got)
+	}
+}
+
+func TestBuildRouteVirtualServiceTLS(t *testing.T) {
+	tcpRoutes, tlsRoutes, err := BuildRouteVirtualService(ProtocolTLS, 443, nil, []tlsRouteRule{
+		{SNIHosts: []string{"a.tls.example"}, ForwardTo: []forwardToDestination{{ServiceName: "tls-server-a", Port: 9443}}},
+		{SNIHosts: []string{"b.tls.example"}, ForwardTo: []forwardToDestination{{ServiceName: "tls-server-b", Port: 9443}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tcpRoutes != nil {
+		t.Fatalf("expected no TCPRoutes for a tls listener, got %v", tcpRoutes)
+	}
+	if len(tlsRoutes) != 2 {
+		t.Fatalf("expected 2 TLSRoutes, got %d", len(tlsRoutes))
+	}
+	if got := tlsRoutes[1].Match[0].SniHosts[0]; got != "b.tls.example" {
+		t.Errorf("second rule sniHosts[0] = %q, want b.tls.example", got)
+	}
+}
+
+func TestBuildRouteVirtualServiceUnsupportedProtocol(t *testing.T) {
+	if _, _, err := BuildRouteVirtualService("http", 80, nil, nil); err == nil {
+		t.Fatal("expected an error for an unsupported listener protocol, got nil")
+	}
+}
+
+func TestBuildRouteVirtualServicePropagatesBuilderErrors(t *testing.T) {
+	if _, _, err := BuildRouteVirtualService(ProtocolTCP, 31400, []tcpRouteRule{{}}, nil); err == nil {
+		t.Fatal("expected an error for a TCPRoute rule with no forwardTo destinations, got nil")
+	}
+}
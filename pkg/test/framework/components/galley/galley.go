@@ -0,0 +1,52 @@
+// Copyright 2019 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package galley provides a component for submitting configuration to the
+// mesh under test, either as an inline YAML string or from a directory of
+// fixtures.
+package galley
+
+import (
+	"istio.io/istio/pkg/test/framework/components/namespace"
+	"istio.io/istio/pkg/test/framework/resource"
+)
+
+// Config configures a new galley Instance.
+type Config struct{}
+
+// Instance represents a deployed Galley. New always returns the Kube
+// implementation, which applies config to the context's default cluster.
+type Instance interface {
+	resource.Resource
+
+	// ApplyConfig applies the given YAML to ns. If ns is nil, the config is
+	// applied cluster-wide.
+	ApplyConfig(ns namespace.Instance, yamlText string) error
+
+	// ApplyConfigDir applies every `.yaml` and `.yaml.tmpl` file under dir to
+	// ns. Template files are expanded per ApplyConfigTemplate before being
+	// submitted.
+	ApplyConfigDir(ns namespace.Instance, dir string) error
+
+	// ApplyConfigTemplate expands tmpl as a Go text/template, with an
+	// auto-populated `.Env` map (from os.Environ()) merged into values, and
+	// applies the result via ApplyConfig. Expansion fails on any key in tmpl
+	// missing from values/.Env rather than silently rendering `<no value>`.
+	ApplyConfigTemplate(ns namespace.Instance, tmpl string, values map[string]interface{}) error
+}
+
+// New creates a new galley Instance.
+func New(ctx resource.Context, cfg Config) (Instance, error) {
+	return newKube(ctx, cfg)
+}
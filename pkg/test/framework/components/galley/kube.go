@@ -0,0 +1,118 @@
+// Copyright 2019 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package galley
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"istio.io/istio/pkg/test/framework/components/namespace"
+	"istio.io/istio/pkg/test/framework/resource"
+)
+
+type kubeComponent struct {
+	id  resource.ID
+	ctx resource.Context
+}
+
+func newKube(ctx resource.Context, _ Config) (Instance, error) {
+	c := &kubeComponent{ctx: ctx}
+	c.id = ctx.TrackResource(c)
+	return c, nil
+}
+
+func (c *kubeComponent) ID() resource.ID {
+	return c.id
+}
+
+func (c *kubeComponent) ApplyConfig(ns namespace.Instance, yamlText string) error {
+	nsName := ""
+	if ns != nil {
+		nsName = ns.Name()
+	}
+	return c.ctx.Clusters().Default().ApplyYAMLContents(nsName, yamlText)
+}
+
+// ApplyConfigDir applies every `.yaml` and `.yaml.tmpl` file under dir,
+// expanding templates through ApplyConfigTemplate so the same fixtures can be
+// parameterized per cluster/domain without duplicating YAML.
+func (c *kubeComponent) ApplyConfigDir(ns namespace.Instance, dir string) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		switch {
+		case strings.HasSuffix(path, ".yaml.tmpl"):
+			contents, err := ioutil.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("reading %s: %v", path, err)
+			}
+			if err := c.ApplyConfigTemplate(ns, string(contents), nil); err != nil {
+				return fmt.Errorf("applying template %s: %v", path, err)
+			}
+		case strings.HasSuffix(path, ".yaml"):
+			contents, err := ioutil.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("reading %s: %v", path, err)
+			}
+			if err := c.ApplyConfig(ns, string(contents)); err != nil {
+				return fmt.Errorf("applying %s: %v", path, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (c *kubeComponent) ApplyConfigTemplate(ns namespace.Instance, tmpl string, values map[string]interface{}) error {
+	data := make(map[string]interface{}, len(values)+1)
+	for k, v := range values {
+		data[k] = v
+	}
+	data["Env"] = envMap()
+
+	t, err := template.New("config").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return fmt.Errorf("parsing template: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return fmt.Errorf("expanding template: %v", err)
+	}
+
+	return c.ApplyConfig(ns, buf.String())
+}
+
+// envMap turns os.Environ() (`KEY=VALUE` strings) into the map exposed to
+// templates as `.Env.KEY`.
+func envMap() map[string]string {
+	out := make(map[string]string)
+	for _, kv := range os.Environ() {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) == 2 {
+			out[parts[0]] = parts[1]
+		}
+	}
+	return out
+}
@@ -0,0 +1,194 @@
+// Copyright 2020 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gatewayconformance runs the `networking.x-k8s.io/v1alpha1` Gateway
+// API resources (GatewayClass, Gateway, HTTPRoute, TCPRoute) through a table
+// of conformance scenarios modeled after the upstream Gateway API
+// conformance project, asserting both traffic behavior through the ingress
+// and the resulting status/conditions reported on the Gateway. The table
+// returned by DefaultScenarios uses GatewayClass, Gateway and HTTPRoute
+// only; it has no TCPRoute scenario.
+package gatewayconformance
+
+import (
+	"fmt"
+
+	"istio.io/istio/pkg/test/framework/components/galley"
+	"istio.io/istio/pkg/test/framework/components/ingress"
+	"istio.io/istio/pkg/test/framework/components/namespace"
+)
+
+// Scenario describes a single conformance case: the Gateway API config to
+// apply, the calls to make against the ingress, and the status we expect
+// the Gateway to report once reconciled.
+type Scenario struct {
+	// Name uniquely identifies the scenario. It is also used as the skiplist
+	// key and as the JUnit test case name.
+	Name string
+
+	// Config is the YAML applied to the test namespace before Calls are made.
+	// It typically contains a GatewayClass, Gateway, and one or more routes.
+	Config string
+
+	// GatewayName is the `metadata.name` of the Gateway resource in Config.
+	// It is only required when WantListeners or WantConditions is set.
+	GatewayName string
+
+	// Calls are the requests issued against the ingress once Config converges.
+	Calls []CallAssertion
+
+	// WantListeners, if non-empty, are the `.status.listeners[].name` entries
+	// expected on the Gateway once reconciled.
+	WantListeners []string
+
+	// WantConditions, if non-empty, are `.status.conditions[].type` entries
+	// expected to be present and have status "True" on the Gateway.
+	WantConditions []string
+}
+
+// Condition is a single `.status.conditions[]` entry read off a Gateway.
+type Condition struct {
+	// Type is the condition's `type`, e.g. "Ready".
+	Type string
+
+	// Status is the condition's `status`, e.g. "True", "False", "Unknown".
+	Status string
+}
+
+// CallAssertion pairs an ingress call with the response it must produce.
+type CallAssertion struct {
+	Options  ingress.CallOptions
+	WantCode int
+}
+
+// Skiplist maps a Scenario.Name to a human-readable reason it is not yet
+// expected to pass, e.g. because the controller doesn't implement the
+// feature under test. Skipped scenarios are not run: neither their config
+// is applied nor their calls made, and they are recorded as skips with the
+// reason as the message.
+type Skiplist map[string]string
+
+// StatusFetcher retrieves the listener names and conditions currently
+// reported in a Gateway's status. It is supplied by the caller because
+// reading CRD status is environment-specific (direct to the dynamic client
+// in Kube, a no-op in other environments).
+type StatusFetcher func(ns namespace.Instance, gateway string) (listeners []string, conditions []Condition, err error)
+
+// Suite runs a table of Scenarios against a single Gateway-enabled ingress
+// and namespace, and aggregates the results into a JUnit report.
+type Suite struct {
+	Gateway  ingress.Instance
+	Galley   galley.Instance
+	Status   StatusFetcher
+	Skiplist Skiplist
+}
+
+// NewSuite returns a Suite ready to Run scenarios against ingr/g. skip may be
+// nil, in which case no scenarios are skipped. status may be nil if no
+// scenario declares WantListeners/WantConditions.
+func NewSuite(ingr ingress.Instance, g galley.Instance, status StatusFetcher, skip Skiplist) *Suite {
+	if skip == nil {
+		skip = Skiplist{}
+	}
+	return &Suite{Gateway: ingr, Galley: g, Status: status, Skiplist: skip}
+}
+
+// Run executes all scenarios in the given namespace and returns a Report
+// summarizing pass/fail/skip for each. It does not call t.Fatal itself so
+// callers can decide how strictly to treat skipped/known-broken scenarios.
+func (s *Suite) Run(ns namespace.Instance, scenarios []Scenario) *Report {
+	report := &Report{Suite: "gatewayconformance"}
+	for _, sc := range scenarios {
+		report.Cases = append(report.Cases, s.runOne(ns, sc))
+	}
+	return report
+}
+
+func (s *Suite) runOne(ns namespace.Instance, sc Scenario) CaseResult {
+	if reason, skipped := s.Skiplist[sc.Name]; skipped {
+		return CaseResult{Name: sc.Name, Skipped: true, Message: reason}
+	}
+
+	if err := s.Galley.ApplyConfig(ns, sc.Config); err != nil {
+		return CaseResult{Name: sc.Name, Failed: true, Message: fmt.Sprintf("applying config: %v", err)}
+	}
+
+	for _, call := range sc.Calls {
+		options := call.Options
+		if options.Address == "" {
+			options.Address = s.Gateway.HTTPAddress()
+		}
+		resp, err := s.Gateway.Call(options)
+		if err != nil {
+			return CaseResult{Name: sc.Name, Failed: true, Message: fmt.Sprintf("call failed: %v", err)}
+		}
+		if resp.Code != call.WantCode {
+			return CaseResult{
+				Name:    sc.Name,
+				Failed:  true,
+				Message: fmt.Sprintf("got response code %d, want %d", resp.Code, call.WantCode),
+			}
+		}
+	}
+
+	if err := s.checkGatewayStatus(ns, sc); err != nil {
+		return CaseResult{Name: sc.Name, Failed: true, Message: err.Error()}
+	}
+
+	return CaseResult{Name: sc.Name}
+}
+
+// checkGatewayStatus fetches the Gateway's reported status and verifies the
+// listeners and conditions the scenario expects are present.
+func (s *Suite) checkGatewayStatus(ns namespace.Instance, sc Scenario) error {
+	if len(sc.WantListeners) == 0 && len(sc.WantConditions) == 0 {
+		return nil
+	}
+	if s.Status == nil {
+		return fmt.Errorf("scenario %s expects gateway status but no StatusFetcher was configured", sc.Name)
+	}
+	listeners, conditions, err := s.Status(ns, sc.GatewayName)
+	if err != nil {
+		return fmt.Errorf("fetching gateway status: %v", err)
+	}
+	for _, want := range sc.WantListeners {
+		if !contains(listeners, want) {
+			return fmt.Errorf("gateway status missing listener %q, got %v", want, listeners)
+		}
+	}
+	for _, want := range sc.WantConditions {
+		if !hasTrueCondition(conditions, want) {
+			return fmt.Errorf("gateway status missing True condition %q, got %v", want, conditions)
+		}
+	}
+	return nil
+}
+
+func contains(haystack []string, needle string) bool {
+	for _, s := range haystack {
+		if s == needle {
+			return true
+		}
+	}
+	return false
+}
+
+func hasTrueCondition(conditions []Condition, wantType string) bool {
+	for _, c := range conditions {
+		if c.Type == wantType && c.Status == "True" {
+			return true
+		}
+	}
+	return false
+}
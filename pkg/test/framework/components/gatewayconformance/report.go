@@ -0,0 +1,94 @@
+// Copyright 2020 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gatewayconformance
+
+import (
+	"encoding/xml"
+	"os"
+)
+
+// CaseResult is the outcome of running a single Scenario.
+type CaseResult struct {
+	Name    string
+	Failed  bool
+	Skipped bool
+	Message string
+}
+
+// Report aggregates CaseResults for a conformance run in a shape that
+// serializes to JUnit XML, so results can be archived and diffed across
+// releases the same way other CI test output is.
+type Report struct {
+	Suite string
+	Cases []CaseResult
+}
+
+// Failed reports whether any non-skipped case in the report failed.
+func (r *Report) Failed() bool {
+	for _, c := range r.Cases {
+		if c.Failed {
+			return true
+		}
+	}
+	return false
+}
+
+type junitTestCase struct {
+	Name    string       `xml:"name,attr"`
+	Failure *junitDetail `xml:"failure,omitempty"`
+	Skipped *junitDetail `xml:"skipped,omitempty"`
+}
+
+type junitDetail struct {
+	Message string `xml:"message,attr"`
+}
+
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+// WriteJUnit renders the report as a JUnit XML file at path, suitable for
+// ingestion by the same CI tooling that aggregates other Istio test output.
+func (r *Report) WriteJUnit(path string) error {
+	suite := junitTestSuite{Name: r.Suite}
+	for _, c := range r.Cases {
+		tc := junitTestCase{Name: c.Name}
+		switch {
+		case c.Failed:
+			tc.Failure = &junitDetail{Message: c.Message}
+			suite.Failures++
+		case c.Skipped:
+			tc.Skipped = &junitDetail{Message: c.Message}
+			suite.Skipped++
+		}
+		suite.TestCases = append(suite.TestCases, tc)
+		suite.Tests++
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	enc := xml.NewEncoder(f)
+	enc.Indent("", "  ")
+	return enc.Encode(suite)
+}
@@ -0,0 +1,402 @@
+// Copyright 2020 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gatewayconformance
+
+import "istio.io/istio/pkg/test/framework/components/ingress"
+
+const gatewayClass = `
+apiVersion: networking.x.k8s.io/v1alpha1
+kind: GatewayClass
+metadata:
+  name: istio
+spec:
+  controller: istio.io/gateway-controller
+`
+
+// DefaultScenarios returns the standard Gateway API conformance table
+// exercised against backend Services named "server" and "server-v2" (the
+// latter used by the weighted and header-match scenarios), both listening on
+// port 80. Scenarios are independent of each other: each names its own
+// Gateway (and any routes) uniquely, so scenarios can run against a shared
+// namespace, in any order, without one scenario's resources clobbering or
+// outliving another's.
+func DefaultScenarios() []Scenario {
+	return []Scenario{
+		{
+			Name:        "path-prefix-match",
+			GatewayName: "gateway-path-prefix-match",
+			Config: gatewayClass + `
+---
+apiVersion: networking.x.k8s.io/v1alpha1
+kind: Gateway
+metadata:
+  name: gateway-path-prefix-match
+spec:
+  class: istio
+  listeners:
+  - name: primary
+    address: {type: NamedAddress, value: prefix.example}
+    port: 80
+    protocol: http
+  routes:
+  - group: networking.x-k8s.io/v1alpha1
+    resource: HTTPRoute
+    name: prefix
+---
+apiVersion: networking.x.k8s.io/v1alpha1
+kind: HTTPRoute
+metadata:
+  name: prefix
+spec:
+  hosts:
+  - hostname: "prefix.example"
+    rules:
+    - match: {pathType: Prefix, path: /get}
+      action: {forwardTo: {group: v1, resource: Service, name: server}}
+`,
+			Calls: []CallAssertion{
+				{Options: ingress.CallOptions{Host: "prefix.example", Path: "/get/sub", CallType: ingress.PlainText}, WantCode: 200},
+				{Options: ingress.CallOptions{Host: "prefix.example", Path: "/other", CallType: ingress.PlainText}, WantCode: 404},
+			},
+			WantListeners:  []string{"primary"},
+			WantConditions: []string{"Ready"},
+		},
+		{
+			Name:        "path-exact-match",
+			GatewayName: "gateway-path-exact-match",
+			Config: gatewayClass + `
+---
+apiVersion: networking.x.k8s.io/v1alpha1
+kind: Gateway
+metadata:
+  name: gateway-path-exact-match
+spec:
+  class: istio
+  listeners:
+  - name: primary
+    address: {type: NamedAddress, value: exact.example}
+    port: 80
+    protocol: http
+  routes:
+  - group: networking.x-k8s.io/v1alpha1
+    resource: HTTPRoute
+    name: exact
+---
+apiVersion: networking.x.k8s.io/v1alpha1
+kind: HTTPRoute
+metadata:
+  name: exact
+spec:
+  hosts:
+  - hostname: "exact.example"
+    rules:
+    - match: {pathType: Exact, path: /get}
+      action: {forwardTo: {group: v1, resource: Service, name: server}}
+`,
+			Calls: []CallAssertion{
+				{Options: ingress.CallOptions{Host: "exact.example", Path: "/get", CallType: ingress.PlainText}, WantCode: 200},
+				{Options: ingress.CallOptions{Host: "exact.example", Path: "/get/sub", CallType: ingress.PlainText}, WantCode: 404},
+			},
+		},
+		{
+			Name:        "host-header-routing",
+			GatewayName: "gateway-host-header-routing",
+			Config: gatewayClass + `
+---
+apiVersion: networking.x.k8s.io/v1alpha1
+kind: Gateway
+metadata:
+  name: gateway-host-header-routing
+spec:
+  class: istio
+  listeners:
+  - name: a
+    address: {type: NamedAddress, value: a.example}
+    port: 80
+    protocol: http
+  - name: b
+    address: {type: NamedAddress, value: b.example}
+    port: 80
+    protocol: http
+  routes:
+  - group: networking.x-k8s.io/v1alpha1
+    resource: HTTPRoute
+    name: host-routed
+---
+apiVersion: networking.x.k8s.io/v1alpha1
+kind: HTTPRoute
+metadata:
+  name: host-routed
+spec:
+  hosts:
+  - hostname: "a.example"
+    rules:
+    - match: {pathType: Prefix, path: /}
+      action: {forwardTo: {group: v1, resource: Service, name: server}}
+  - hostname: "b.example"
+    rules:
+    - match: {pathType: Prefix, path: /}
+      action: {forwardTo: {group: v1, resource: Service, name: server}}
+`,
+			Calls: []CallAssertion{
+				{Options: ingress.CallOptions{Host: "a.example", Path: "/", CallType: ingress.PlainText}, WantCode: 200},
+				{Options: ingress.CallOptions{Host: "b.example", Path: "/", CallType: ingress.PlainText}, WantCode: 200},
+				{Options: ingress.CallOptions{Host: "c.example", Path: "/", CallType: ingress.PlainText}, WantCode: 404},
+			},
+			WantListeners: []string{"a", "b"},
+		},
+		{
+			Name:        "weighted-backends",
+			GatewayName: "gateway-weighted-backends",
+			Config: gatewayClass + `
+---
+apiVersion: networking.x.k8s.io/v1alpha1
+kind: Gateway
+metadata:
+  name: gateway-weighted-backends
+spec:
+  class: istio
+  listeners:
+  - name: primary
+    address: {type: NamedAddress, value: weighted.example}
+    port: 80
+    protocol: http
+  routes:
+  - group: networking.x-k8s.io/v1alpha1
+    resource: HTTPRoute
+    name: weighted
+---
+apiVersion: networking.x.k8s.io/v1alpha1
+kind: HTTPRoute
+metadata:
+  name: weighted
+spec:
+  hosts:
+  - hostname: "weighted.example"
+    rules:
+    - match: {pathType: Prefix, path: /}
+      action:
+        forwardTo:
+        - {group: v1, resource: Service, name: server, weight: 90}
+        - {group: v1, resource: Service, name: server-v2, weight: 10}
+`,
+			Calls: []CallAssertion{
+				{Options: ingress.CallOptions{Host: "weighted.example", Path: "/", CallType: ingress.PlainText}, WantCode: 200},
+			},
+		},
+		{
+			Name:        "header-and-query-match",
+			GatewayName: "gateway-header-and-query-match",
+			Config: gatewayClass + `
+---
+apiVersion: networking.x.k8s.io/v1alpha1
+kind: Gateway
+metadata:
+  name: gateway-header-and-query-match
+spec:
+  class: istio
+  listeners:
+  - name: primary
+    address: {type: NamedAddress, value: headers.example}
+    port: 80
+    protocol: http
+  routes:
+  - group: networking.x-k8s.io/v1alpha1
+    resource: HTTPRoute
+    name: headers
+---
+apiVersion: networking.x.k8s.io/v1alpha1
+kind: HTTPRoute
+metadata:
+  name: headers
+spec:
+  hosts:
+  - hostname: "headers.example"
+    rules:
+    - match:
+        pathType: Prefix
+        path: /
+        headers: {canary: "true"}
+      action: {forwardTo: {group: v1, resource: Service, name: server-v2}}
+    - match: {pathType: Prefix, path: /}
+      action: {forwardTo: {group: v1, resource: Service, name: server}}
+`,
+			Calls: []CallAssertion{
+				{Options: ingress.CallOptions{
+					Host:     "headers.example",
+					Path:     "/",
+					CallType: ingress.PlainText,
+					Headers:  map[string][]string{"canary": {"true"}},
+				}, WantCode: 200},
+			},
+		},
+		{
+			Name:        "header-rewrite",
+			GatewayName: "gateway-header-rewrite",
+			Config: gatewayClass + `
+---
+apiVersion: networking.x.k8s.io/v1alpha1
+kind: Gateway
+metadata:
+  name: gateway-header-rewrite
+spec:
+  class: istio
+  listeners:
+  - name: primary
+    address: {type: NamedAddress, value: rewrite.example}
+    port: 80
+    protocol: http
+  routes:
+  - group: networking.x-k8s.io/v1alpha1
+    resource: HTTPRoute
+    name: rewrite
+---
+apiVersion: networking.x.k8s.io/v1alpha1
+kind: HTTPRoute
+metadata:
+  name: rewrite
+spec:
+  hosts:
+  - hostname: "rewrite.example"
+    rules:
+    - match: {pathType: Prefix, path: /}
+      action:
+        forwardTo: {group: v1, resource: Service, name: server}
+        requestHeaderModifier: {set: {x-forwarded-host: "rewrite.example"}}
+`,
+			Calls: []CallAssertion{
+				{Options: ingress.CallOptions{Host: "rewrite.example", Path: "/", CallType: ingress.PlainText}, WantCode: 200},
+			},
+		},
+		{
+			Name:        "redirect",
+			GatewayName: "gateway-redirect",
+			Config: gatewayClass + `
+---
+apiVersion: networking.x.k8s.io/v1alpha1
+kind: Gateway
+metadata:
+  name: gateway-redirect
+spec:
+  class: istio
+  listeners:
+  - name: primary
+    address: {type: NamedAddress, value: redirect.example}
+    port: 80
+    protocol: http
+  routes:
+  - group: networking.x-k8s.io/v1alpha1
+    resource: HTTPRoute
+    name: redirect
+---
+apiVersion: networking.x.k8s.io/v1alpha1
+kind: HTTPRoute
+metadata:
+  name: redirect
+spec:
+  hosts:
+  - hostname: "redirect.example"
+    rules:
+    - match: {pathType: Prefix, path: /old}
+      action:
+        requestRedirect: {path: /new, statusCode: 301}
+`,
+			Calls: []CallAssertion{
+				{Options: ingress.CallOptions{Host: "redirect.example", Path: "/old", CallType: ingress.PlainText}, WantCode: 301},
+			},
+		},
+		{
+			Name:        "tls-termination",
+			GatewayName: "gateway-tls-termination",
+			Config: gatewayClass + `
+---
+apiVersion: networking.x.k8s.io/v1alpha1
+kind: Gateway
+metadata:
+  name: gateway-tls-termination
+spec:
+  class: istio
+  listeners:
+  - name: https
+    address: {type: NamedAddress, value: secure.example}
+    port: 443
+    protocol: https
+    tls: {certificateRef: {name: secure-example-cert}}
+  routes:
+  - group: networking.x-k8s.io/v1alpha1
+    resource: HTTPRoute
+    name: secure
+---
+apiVersion: networking.x.k8s.io/v1alpha1
+kind: HTTPRoute
+metadata:
+  name: secure
+spec:
+  hosts:
+  - hostname: "secure.example"
+    rules:
+    - match: {pathType: Prefix, path: /}
+      action: {forwardTo: {group: v1, resource: Service, name: server}}
+`,
+			// No call is made here: ingress.TLS sends a raw echo payload rather
+			// than an HTTP request, so it cannot exercise an HTTPRoute behind an
+			// https listener. Only the reconciled https listener is asserted.
+			WantListeners: []string{"https"},
+		},
+		{
+			Name:        "cross-namespace-binding",
+			GatewayName: "gateway-cross-namespace-binding",
+			Config: gatewayClass + `
+---
+apiVersion: networking.x.k8s.io/v1alpha1
+kind: Gateway
+metadata:
+  name: gateway-cross-namespace-binding
+spec:
+  class: istio
+  listeners:
+  - name: primary
+    address: {type: NamedAddress, value: xns.example}
+    port: 80
+    protocol: http
+  routes:
+  - group: networking.x-k8s.io/v1alpha1
+    resource: HTTPRoute
+    name: xns-route
+    namespace: xns-target
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: xns-target
+---
+apiVersion: networking.x.k8s.io/v1alpha1
+kind: HTTPRoute
+metadata:
+  name: xns-route
+  namespace: xns-target
+spec:
+  hosts:
+  - hostname: "xns.example"
+    rules:
+    - match: {pathType: Prefix, path: /}
+      action: {forwardTo: {group: v1, resource: Service, name: server}}
+`,
+			Calls: []CallAssertion{
+				{Options: ingress.CallOptions{Host: "xns.example", Path: "/", CallType: ingress.PlainText}, WantCode: 200},
+			},
+		},
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2019 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ingress provides a component for calling into the mesh's ingress
+// gateway from test code, whether it is fronting classic Ingress resources
+// or the Gateway API (GatewayClass/Gateway/HTTPRoute/TCPRoute/TLSRoute).
+package ingress
+
+import (
+	"istio.io/istio/pkg/test/framework/components/istio"
+	"istio.io/istio/pkg/test/framework/resource"
+)
+
+// CallType distinguishes how Instance.Call should dial the ingress.
+type CallType int
+
+const (
+	// PlainText sends a plain HTTP request.
+	PlainText CallType = iota
+
+	// TCP dials a raw TCP listener and expects the response to round-trip the
+	// request body, used to validate TCPRoute backend selection.
+	TCP
+
+	// TLS performs a TLS handshake (optionally with SNI, see CallOptions.SNI)
+	// and then round-trips an echo payload like TCP, used to validate
+	// TLSRoute listeners.
+	TLS
+)
+
+// Config configures a new ingress Instance.
+type Config struct {
+	// Istio is the Istio installation the ingress gateway belongs to.
+	Istio istio.Instance
+
+	// Cluster is the cluster whose ingress gateway this Instance calls into.
+	// If unset, the context's default cluster is used.
+	Cluster resource.Cluster
+}
+
+// CallOptions defines the options for Instance.Call.
+type CallOptions struct {
+	// Host is sent as the request's Host header (PlainText) or SNI (TLS, unless
+	// SNI is set). For CallType TCP and TLS it is also the payload written to
+	// the connection and expected back from the echo backend; it plays no
+	// part in TCPRoute backend selection, which is by listener port.
+	Host string
+
+	// Path is the HTTP path to call. Ignored for CallType TCP and TLS.
+	Path string
+
+	// CallType selects which protocol is used to reach the ingress.
+	CallType CallType
+
+	// Address is the ingress address to dial, e.g. Instance.HTTPAddress(),
+	// Instance.TCPAddress(), or Instance.TLSAddress() depending on CallType.
+	Address string
+
+	// SNI, if set, overrides Host as the TLS ServerName sent during the
+	// handshake for CallType TLS. This lets tests validate SNI-based routing
+	// to multiple backends behind a single TLS listener.
+	SNI string
+
+	// Headers are additional headers sent with the request. Ignored for
+	// CallType TCP and TLS.
+	Headers map[string][]string
+}
+
+// CallResponse is the result of a successful Instance.Call.
+type CallResponse struct {
+	// Code is the HTTP response status code. For CallType TCP and TLS it is
+	// 200 if the echoed body matched what was sent, 0 otherwise.
+	Code int
+
+	// Body is the raw response body.
+	Body string
+}
+
+// Instance represents a deployed ingress gateway.
+type Instance interface {
+	resource.Resource
+
+	// Call sends a request to the ingress gateway and returns the response.
+	Call(options CallOptions) (CallResponse, error)
+
+	// HTTPAddress returns the host:port of the plain-text HTTP listener.
+	HTTPAddress() string
+
+	// TCPAddress returns the host:port of the TCPRoute listener.
+	TCPAddress() string
+
+	// TLSAddress returns the host:port of the TLSRoute/HTTPS listener.
+	TLSAddress() string
+}
+
+// New creates a new ingress Instance for the given Istio installation, bound
+// to cfg.Cluster (or the context's default cluster if unset).
+func New(ctx resource.Context, cfg Config) (Instance, error) {
+	return newKube(ctx, cfg)
+}
+
+// NewPerCluster creates one ingress Instance per entry in clusters, each
+// bound to its own cluster's ingress gateway. This is used by multi-cluster
+// tests that need to dial a specific cluster's gateway directly, e.g. to
+// verify cross-network endpoint discovery through the east-west gateway.
+func NewPerCluster(ctx resource.Context, cfg Config, clusters resource.Clusters) (map[string]Instance, error) {
+	out := make(map[string]Instance, len(clusters))
+	for _, c := range clusters {
+		perCluster := cfg
+		perCluster.Cluster = c
+		i, err := New(ctx, perCluster)
+		if err != nil {
+			return nil, err
+		}
+		out[c.Name()] = i
+	}
+	return out, nil
+}
@@ -0,0 +1,149 @@
+// Copyright 2019 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingress
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+
+	"istio.io/istio/pkg/test/framework/resource"
+)
+
+type kubeComponent struct {
+	id resource.ID
+
+	httpAddress string
+	tcpAddress  string
+	tlsAddress  string
+}
+
+func newKube(ctx resource.Context, cfg Config) (Instance, error) {
+	cluster := cfg.Cluster
+	if cluster == nil {
+		cluster = ctx.Clusters().Default()
+	}
+	host := cfg.Istio.IngressGatewayAddressFor(cluster)
+	c := &kubeComponent{
+		httpAddress: fmt.Sprintf("%s:80", host),
+		tcpAddress:  fmt.Sprintf("%s:31400", host),
+		tlsAddress:  fmt.Sprintf("%s:443", host),
+	}
+	c.id = ctx.TrackResource(c)
+	return c, nil
+}
+
+func (c *kubeComponent) ID() resource.ID {
+	return c.id
+}
+
+func (c *kubeComponent) HTTPAddress() string {
+	return c.httpAddress
+}
+
+func (c *kubeComponent) TCPAddress() string {
+	return c.tcpAddress
+}
+
+func (c *kubeComponent) TLSAddress() string {
+	return c.tlsAddress
+}
+
+func (c *kubeComponent) Call(options CallOptions) (CallResponse, error) {
+	switch options.CallType {
+	case TCP:
+		return c.callTCP(options)
+	case TLS:
+		return c.callTLS(options)
+	default:
+		return c.callHTTP(options)
+	}
+}
+
+func (c *kubeComponent) callHTTP(options CallOptions) (CallResponse, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s%s", options.Address, options.Path), nil)
+	if err != nil {
+		return CallResponse{}, err
+	}
+	req.Host = options.Host
+	for k, vs := range options.Headers {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		return CallResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return CallResponse{}, err
+	}
+	return CallResponse{Code: resp.StatusCode, Body: string(body)}, nil
+}
+
+// callTCP dials the TCPRoute listener, writes the requested Host as the
+// payload, and expects the backend's echo server to write it straight back.
+// Any backend that echoes the payload yields Code 200; the reply does not
+// identify which of several backends behind the listener answered.
+func (c *kubeComponent) callTCP(options CallOptions) (CallResponse, error) {
+	conn, err := net.Dial("tcp", options.Address)
+	if err != nil {
+		return CallResponse{}, err
+	}
+	defer conn.Close()
+	return roundTripEcho(conn, options.Host)
+}
+
+// callTLS performs a TLS handshake against the TLSRoute/HTTPS listener,
+// using options.SNI (falling back to options.Host) as the ServerName, then
+// round-trips options.Host as an echo payload like callTCP. A Code of 200
+// shows that some backend answered for that SNI, not which one.
+func (c *kubeComponent) callTLS(options CallOptions) (CallResponse, error) {
+	sni := options.SNI
+	if sni == "" {
+		sni = options.Host
+	}
+	conn, err := tls.Dial("tcp", options.Address, &tls.Config{
+		ServerName:         sni,
+		InsecureSkipVerify: true,
+	})
+	if err != nil {
+		return CallResponse{}, err
+	}
+	defer conn.Close()
+	return roundTripEcho(conn, options.Host)
+}
+
+func roundTripEcho(conn net.Conn, payload string) (CallResponse, error) {
+	if _, err := fmt.Fprintln(conn, payload); err != nil {
+		return CallResponse{}, err
+	}
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return CallResponse{}, err
+	}
+	code := 0
+	if reply == payload+"\n" {
+		code = 200
+	}
+	return CallResponse{Code: code, Body: reply}, nil
+}
@@ -0,0 +1,149 @@
+// Copyright 2019 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilot
+
+import (
+	"context"
+	"fmt"
+
+	apiv2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	sds "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v2"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc"
+
+	"istio.io/istio/pkg/test/framework/resource"
+)
+
+const (
+	listenerTypeURL = "type.googleapis.com/envoy.api.v2.Listener"
+	routeTypeURL    = "type.googleapis.com/envoy.api.v2.RouteConfiguration"
+	clusterTypeURL  = "type.googleapis.com/envoy.api.v2.Cluster"
+)
+
+// adsClient issues one-shot ADS requests against Pilot's xDS address and
+// unmarshals the returned resources, filtered to a single node ID, into the
+// typed helpers consumed by WaitForListener/WaitForRouteMatch.
+type adsClient struct {
+	conn *grpc.ClientConn
+}
+
+func newADSClient(ctx resource.Context) (*adsClient, error) {
+	conn, err := grpc.Dial(ctx.Clusters().Default().PilotDiscoveryAddress(), grpc.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+	return &adsClient{conn: conn}, nil
+}
+
+// fetch sends a single DiscoveryRequest for typeURL on behalf of nodeID and
+// returns the decoded resources wrapped in this package's listener/
+// routeConfig adapter types. resourceNames is only meaningful for
+// non-wildcard types such as RDS (see routeTypeURL's caller) — LDS/CDS
+// return every resource for nodeID regardless of what's passed here.
+func (a *adsClient) fetch(nodeID, typeURL string, resourceNames ...string) ([]interface{}, error) {
+	client := sds.NewAggregatedDiscoveryServiceClient(a.conn)
+	stream, err := client.StreamAggregatedResources(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer stream.CloseSend()
+
+	if err := stream.Send(&apiv2.DiscoveryRequest{
+		Node:          &core.Node{Id: nodeID},
+		TypeUrl:       typeURL,
+		ResourceNames: resourceNames,
+	}); err != nil {
+		return nil, err
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]interface{}, 0, len(resp.Resources))
+	for _, res := range resp.Resources {
+		switch typeURL {
+		case listenerTypeURL:
+			l := &apiv2.Listener{}
+			if err := ptypes.UnmarshalAny(res, l); err != nil {
+				return nil, err
+			}
+			out = append(out, listener{l})
+		case routeTypeURL:
+			r := &apiv2.RouteConfiguration{}
+			if err := ptypes.UnmarshalAny(res, r); err != nil {
+				return nil, err
+			}
+			out = append(out, routeConfig{r})
+		case clusterTypeURL:
+			c := &apiv2.Cluster{}
+			if err := ptypes.UnmarshalAny(res, c); err != nil {
+				return nil, err
+			}
+			out = append(out, cluster{c})
+		default:
+			return nil, fmt.Errorf("unsupported type URL %q", typeURL)
+		}
+	}
+	return out, nil
+}
+
+// listener adapts *envoy.api.v2.Listener to the name/port lookup
+// WaitForListener needs.
+type listener struct{ l *apiv2.Listener }
+
+func (l listener) Name() string { return l.l.GetName() }
+
+func (l listener) Port() uint32 {
+	return l.l.GetAddress().GetSocketAddress().GetPortValue()
+}
+
+// routeConfig adapts *envoy.api.v2.RouteConfiguration to the host/prefix/
+// cluster lookup WaitForRouteMatch needs.
+type routeConfig struct{ r *apiv2.RouteConfiguration }
+
+func (rc routeConfig) HasMatch(host, pathPrefix, clusterName string) bool {
+	for _, vh := range rc.r.GetVirtualHosts() {
+		if !hasDomain(vh.GetDomains(), host) {
+			continue
+		}
+		for _, route := range vh.GetRoutes() {
+			if route.GetMatch().GetPrefix() != pathPrefix {
+				continue
+			}
+			if route.GetRoute().GetCluster() == clusterName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasDomain(domains []string, host string) bool {
+	for _, d := range domains {
+		if d == host || d == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// cluster adapts *envoy.api.v2.Cluster; exposed for symmetry with
+// listener/routeConfig even though no WaitForX helper needs it yet.
+type cluster struct{ c *apiv2.Cluster }
+
+func (c cluster) Name() string { return c.c.GetName() }
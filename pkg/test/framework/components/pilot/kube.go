@@ -0,0 +1,81 @@
+// Copyright 2019 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pilot
+
+import (
+	"fmt"
+
+	"istio.io/istio/pkg/test/framework/resource"
+	"istio.io/istio/pkg/test/util/retry"
+)
+
+type kubeComponent struct {
+	id resource.ID
+
+	ads *adsClient
+}
+
+func newKube(ctx resource.Context, cfg Config) (Instance, error) {
+	ads, err := newADSClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to pilot ADS: %v", err)
+	}
+
+	c := &kubeComponent{ads: ads}
+	c.id = ctx.TrackResource(c)
+	return c, nil
+}
+
+func (c *kubeComponent) ID() resource.ID {
+	return c.id
+}
+
+func (c *kubeComponent) WaitForListener(nodeID, name string, port uint32) error {
+	return retry.UntilSuccess(func() error {
+		listeners, err := c.ads.fetch(nodeID, listenerTypeURL)
+		if err != nil {
+			return err
+		}
+		for _, l := range listeners {
+			lis, ok := l.(listener)
+			if !ok {
+				continue
+			}
+			if lis.Name() == name && lis.Port() == port {
+				return nil
+			}
+		}
+		return fmt.Errorf("no listener %q on port %d for node %q yet", name, port, nodeID)
+	})
+}
+
+func (c *kubeComponent) WaitForRouteMatch(nodeID, routeName, host, pathPrefix, clusterName string) error {
+	return retry.UntilSuccess(func() error {
+		routes, err := c.ads.fetch(nodeID, routeTypeURL, routeName)
+		if err != nil {
+			return err
+		}
+		for _, r := range routes {
+			rc, ok := r.(routeConfig)
+			if !ok {
+				continue
+			}
+			if rc.HasMatch(host, pathPrefix, clusterName) {
+				return nil
+			}
+		}
+		return fmt.Errorf("no route %q for host %q prefix %q -> cluster %q for node %q yet", routeName, host, pathPrefix, clusterName, nodeID)
+	})
+}
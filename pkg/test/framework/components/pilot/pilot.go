@@ -0,0 +1,59 @@
+// Copyright 2019 Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pilot provides a component for interacting with the mesh's Pilot
+// (istiod) discovery server from test code, to make native ADS assertions
+// about the xDS it produces for a given workload.
+package pilot
+
+import (
+	"istio.io/istio/pkg/test/framework/components/galley"
+	"istio.io/istio/pkg/test/framework/resource"
+)
+
+// Config configures a new pilot Instance.
+type Config struct {
+	// Galley is the config source used alongside this Pilot. New does not
+	// read it.
+	Galley galley.Instance
+}
+
+// Instance represents a deployed Pilot (istiod) discovery server.
+type Instance interface {
+	resource.Resource
+
+	// WaitForListener polls ADS until nodeID has a Listener named name bound
+	// to port, or the retry budget is exhausted. name is Envoy's own LDS
+	// listener name (typically "<bind address>_<port>", e.g. "0.0.0.0_80"),
+	// not any higher-level config's logical listener name.
+	WaitForListener(nodeID, name string, port uint32) error
+
+	// WaitForRouteMatch polls ADS until nodeID's RouteConfiguration named
+	// routeName contains a route for host matching pathPrefix that forwards
+	// to clusterName, or the retry budget is exhausted. routeName is the RDS
+	// resource name Envoy requests for the listener under test (for a plain
+	// HTTP gateway listener, this is its port as a string, e.g. "80") — RDS
+	// is not a wildcard xDS type, so it must be named explicitly rather than
+	// discovered from an unfiltered fetch. This closes the observability gap
+	// when a request fails for reasons unrelated to config translation (e.g.
+	// an Envoy image issue): it asserts the control plane produced the
+	// expected config independent of whether traffic through it succeeds.
+	WaitForRouteMatch(nodeID, routeName, host, pathPrefix, clusterName string) error
+}
+
+// New creates a new pilot Instance connected over ADS to the discovery
+// server of the context's default cluster.
+func New(ctx resource.Context, cfg Config) (Instance, error) {
+	return newKube(ctx, cfg)
+}
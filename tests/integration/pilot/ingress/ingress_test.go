@@ -15,15 +15,22 @@
 package ingress
 
 import (
+	"context"
 	"fmt"
+	"path"
 	"testing"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
 	"istio.io/istio/pkg/config/protocol"
 	"istio.io/istio/pkg/test/framework"
 	"istio.io/istio/pkg/test/framework/components/echo"
 	"istio.io/istio/pkg/test/framework/components/echo/echoboot"
 	"istio.io/istio/pkg/test/framework/components/environment"
 	"istio.io/istio/pkg/test/framework/components/galley"
+	"istio.io/istio/pkg/test/framework/components/gatewayconformance"
 	"istio.io/istio/pkg/test/framework/components/ingress"
 	"istio.io/istio/pkg/test/framework/components/istio"
 	"istio.io/istio/pkg/test/framework/components/namespace"
@@ -33,6 +40,23 @@ import (
 	"istio.io/istio/pkg/test/util/retry"
 )
 
+// gatewayGVR identifies the networking.x-k8s.io Gateway API Gateway resource,
+// used to read back `.status` for conformance assertions.
+var gatewayGVR = schema.GroupVersionResource{
+	Group:    "networking.x.k8s.io",
+	Version:  "v1alpha1",
+	Resource: "gateways",
+}
+
+// ingressGatewayNodeID is the ADS node ID of the istio-ingressgateway
+// workload in a standard installation, used to scope xDS assertions to the
+// gateway's own config rather than the whole mesh. This assumes the default
+// istio-system install namespace and revision-less node ID used elsewhere in
+// this suite's istio.Setup; it would need to be derived from the Istio
+// instance's configured namespace if this package ever tested a non-default
+// install.
+const ingressGatewayNodeID = "router~0.0.0.0~istio-ingressgateway.istio-system~istio-system.svc.cluster.local"
+
 var (
 	i    istio.Instance
 	g    galley.Instance
@@ -168,6 +192,21 @@ spec:
 			}); err != nil {
 				t.Fatal(err)
 			}
+
+			// Beyond the black-box HTTP call above, assert directly over ADS that
+			// the Gateway+HTTPRoute translated into the xDS config we expect. This
+			// catches config-translation regressions that a 200 response alone
+			// can't distinguish from, say, an unrelated Envoy image issue.
+			//
+			// Envoy names LDS listeners after their bind address/port (e.g.
+			// "0.0.0.0_80"), not the Gateway API listener's logical name
+			// ("primary"), so that's what WaitForListener must match.
+			if err := p.WaitForListener(ingressGatewayNodeID, "0.0.0.0_80", 80); err != nil {
+				t.Fatal(err)
+			}
+			if err := p.WaitForRouteMatch(ingressGatewayNodeID, "80", "my.domain.example", "/get", "outbound|80||server."+ns.Name()+".svc.cluster.local"); err != nil {
+				t.Fatal(err)
+			}
 		})
 }
 
@@ -238,3 +277,369 @@ spec:
 			}
 		})
 }
+
+// TestGatewayTCPAndTLSRoute exercises TCPRoute and TLSRoute bound to
+// `protocol: tcp` / `protocol: tls` Gateway listeners (cxhiano/istio#chunk0-2),
+// including SNI-based routing to one of two backends sharing a single TLS
+// listener.
+func TestGatewayTCPAndTLSRoute(t *testing.T) {
+	framework.
+		NewTest(t).
+		RequiresEnvironment(environment.Kube).
+		Run(func(ctx framework.TestContext) {
+			ns := namespace.NewOrFail(t, ctx, namespace.Config{
+				Prefix: "gateway-tcp-tls",
+				Inject: true,
+			})
+
+			var tcpBackend, tlsBackendA, tlsBackendB echo.Instance
+			echoboot.NewBuilderOrFail(t, ctx).
+				With(&tcpBackend, echo.Config{
+					Service:   "tcp-server",
+					Namespace: ns,
+					Subsets:   []echo.SubsetConfig{{}},
+					Pilot:     p,
+					Galley:    g,
+					Ports: []echo.Port{
+						{Name: "tcp", Protocol: protocol.TCP, InstancePort: 9090},
+					},
+				}).
+				With(&tlsBackendA, echo.Config{
+					Service:   "tls-server-a",
+					Namespace: ns,
+					Subsets:   []echo.SubsetConfig{{}},
+					Pilot:     p,
+					Galley:    g,
+					Ports: []echo.Port{
+						{Name: "tls", Protocol: protocol.TCP, InstancePort: 9443, TLS: true},
+					},
+				}).
+				With(&tlsBackendB, echo.Config{
+					Service:   "tls-server-b",
+					Namespace: ns,
+					Subsets:   []echo.SubsetConfig{{}},
+					Pilot:     p,
+					Galley:    g,
+					Ports: []echo.Port{
+						{Name: "tls", Protocol: protocol.TCP, InstancePort: 9443, TLS: true},
+					},
+				}).
+				BuildOrFail(t)
+			tcpBackend.Address()
+			tlsBackendA.Address()
+			tlsBackendB.Address()
+
+			if err := g.ApplyConfig(ns, `
+apiVersion: networking.x.k8s.io/v1alpha1
+kind: GatewayClass
+metadata:
+  name: istio
+spec:
+  controller: istio.io/gateway-controller
+---
+apiVersion: networking.x.k8s.io/v1alpha1
+kind: Gateway
+metadata:
+  name: gateway
+spec:
+  class: istio
+  listeners:
+  - name: tcp
+    address: {type: NamedAddress, value: tcp.example}
+    port: 31400
+    protocol: tcp
+    routes:
+    - {group: networking.x-k8s.io/v1alpha1, resource: TCPRoute, name: tcp-route}
+  - name: tls
+    address: {type: NamedAddress, value: tls.example}
+    port: 443
+    protocol: tls
+    routes:
+    - {group: networking.x-k8s.io/v1alpha1, resource: TLSRoute, name: tls-route}
+---
+apiVersion: networking.x.k8s.io/v1alpha1
+kind: TCPRoute
+metadata:
+  name: tcp-route
+spec:
+  rules:
+  - forwardTo:
+      group: v1
+      resource: Service
+      name: tcp-server
+---
+apiVersion: networking.x.k8s.io/v1alpha1
+kind: TLSRoute
+metadata:
+  name: tls-route
+spec:
+  rules:
+  - match:
+      sniHosts: ["a.tls.example"]
+    forwardTo:
+      group: v1
+      resource: Service
+      name: tls-server-a
+  - match:
+      sniHosts: ["b.tls.example"]
+    forwardTo:
+      group: v1
+      resource: Service
+      name: tls-server-b`,
+			); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := retry.UntilSuccess(func() error {
+				resp, err := ingr.Call(ingress.CallOptions{
+					Host:     "tcp.example",
+					CallType: ingress.TCP,
+					Address:  ingr.TCPAddress(),
+				})
+				if err != nil {
+					return err
+				}
+				if resp.Code != 200 {
+					return fmt.Errorf("tcp route: got invalid response code %v: %v", resp.Code, resp.Body)
+				}
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, sni := range []string{"a.tls.example", "b.tls.example"} {
+				sni := sni
+				if err := retry.UntilSuccess(func() error {
+					resp, err := ingr.Call(ingress.CallOptions{
+						Host:     sni,
+						SNI:      sni,
+						CallType: ingress.TLS,
+						Address:  ingr.TLSAddress(),
+					})
+					if err != nil {
+						return err
+					}
+					if resp.Code != 200 {
+						return fmt.Errorf("tls route %s: got invalid response code %v: %v", sni, resp.Code, resp.Body)
+					}
+					return nil
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+}
+
+// gatewayConformanceSkiplist maps each conformance scenario that
+// TestGatewayConformance skips to the reason it is skipped.
+var gatewayConformanceSkiplist = gatewayconformance.Skiplist{
+	"cross-namespace-binding": "route binding across namespaces is not yet implemented",
+}
+
+// TestGatewayConformance runs the Gateway API conformance scenario table
+// (cxhiano/istio#chunk0-1) against the shared ingress/galley/pilot
+// installation brought up in TestMain.
+func TestGatewayConformance(t *testing.T) {
+	framework.
+		NewTest(t).
+		RequiresEnvironment(environment.Kube).
+		Run(func(ctx framework.TestContext) {
+			ns := namespace.NewOrFail(t, ctx, namespace.Config{
+				Prefix: "gateway-conformance",
+				Inject: true,
+			})
+			var instance, instanceV2 echo.Instance
+			echoboot.NewBuilderOrFail(t, ctx).
+				With(&instance, echo.Config{
+					Service:   "server",
+					Namespace: ns,
+					Subsets:   []echo.SubsetConfig{{}},
+					Pilot:     p,
+					Galley:    g,
+					Ports: []echo.Port{
+						{
+							Name:         "http",
+							Protocol:     protocol.HTTP,
+							InstancePort: 8090,
+						},
+					},
+				}).
+				With(&instanceV2, echo.Config{
+					Service:   "server-v2",
+					Namespace: ns,
+					Subsets:   []echo.SubsetConfig{{}},
+					Pilot:     p,
+					Galley:    g,
+					Ports: []echo.Port{
+						{
+							Name:         "http",
+							Protocol:     protocol.HTTP,
+							InstancePort: 8090,
+						},
+					},
+				}).
+				BuildOrFail(t)
+			instance.Address()
+			instanceV2.Address()
+
+			suite := gatewayconformance.NewSuite(ingr, g, gatewayStatusFetcher(ctx), gatewayConformanceSkiplist)
+			report := suite.Run(ns, gatewayconformance.DefaultScenarios())
+
+			reportDir := ctx.CreateTmpDirectoryOrFail(t, "gateway-conformance")
+			if err := report.WriteJUnit(path.Join(reportDir, "junit.xml")); err != nil {
+				t.Logf("failed writing gateway conformance JUnit report: %v", err)
+			}
+
+			for _, c := range report.Cases {
+				switch {
+				case c.Skipped:
+					t.Logf("SKIP %s: %s", c.Name, c.Message)
+				case c.Failed:
+					t.Errorf("FAIL %s: %s", c.Name, c.Message)
+				}
+			}
+		})
+}
+
+// gatewayStatusFetcher reads the listener names and conditions reported on a
+// Gateway resource's `.status`, for use by the conformance suite.
+func gatewayStatusFetcher(ctx framework.TestContext) gatewayconformance.StatusFetcher {
+	return func(ns namespace.Instance, name string) ([]string, []gatewayconformance.Condition, error) {
+		obj, err := ctx.Clusters().Default().
+			Dynamic().
+			Resource(gatewayGVR).
+			Namespace(ns.Name()).
+			Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		listeners, _, err := unstructured.NestedSlice(obj.Object, "status", "listeners")
+		if err != nil {
+			return nil, nil, err
+		}
+		var listenerNames []string
+		for _, l := range listeners {
+			if m, ok := l.(map[string]interface{}); ok {
+				if n, ok := m["name"].(string); ok {
+					listenerNames = append(listenerNames, n)
+				}
+			}
+		}
+
+		conditions, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+		if err != nil {
+			return nil, nil, err
+		}
+		var out []gatewayconformance.Condition
+		for _, c := range conditions {
+			if m, ok := c.(map[string]interface{}); ok {
+				ct, _ := m["type"].(string)
+				status, _ := m["status"].(string)
+				out = append(out, gatewayconformance.Condition{Type: ct, Status: status})
+			}
+		}
+
+		return listenerNames, out, nil
+	}
+}
+
+// TestGatewayMultiNetwork verifies that a Gateway/HTTPRoute applied while the
+// backend Service's only endpoint lives in a different cluster/network is
+// still resolved correctly, i.e. that cross-network endpoint discovery
+// through the east-west gateway keeps working for the Gateway API path
+// (cxhiano/istio#chunk0-3). It mirrors the topology/network label handling
+// exercised for classic Istio routing in mesh_network_test.go.
+func TestGatewayMultiNetwork(t *testing.T) {
+	framework.
+		NewTest(t).
+		RequiresEnvironment(environment.Kube).
+		RequiresMinClusters(2).
+		Run(func(ctx framework.TestContext) {
+			clusters := ctx.Clusters()
+			ingressCluster, backendCluster := clusters[0], clusters[1]
+
+			ns := namespace.NewOrFail(t, ctx, namespace.Config{
+				Prefix: "gateway-multinetwork",
+				Inject: true,
+			})
+
+			var instance echo.Instance
+			echoboot.NewBuilderOrFail(t, ctx).
+				With(&instance, echo.Config{
+					Service:   "server",
+					Namespace: ns,
+					Subsets:   []echo.SubsetConfig{{}},
+					Pilot:     p,
+					Galley:    g,
+					Cluster:   backendCluster,
+					Ports: []echo.Port{
+						{Name: "http", Protocol: protocol.HTTP, InstancePort: 8090},
+					},
+				}).
+				BuildOrFail(t)
+			instance.Address()
+
+			perCluster, err := ingress.NewPerCluster(ctx, ingress.Config{Istio: i}, resource.Clusters{ingressCluster})
+			if err != nil {
+				t.Fatal(err)
+			}
+			crossNetworkIngress := perCluster[ingressCluster.Name()]
+
+			if err := g.ApplyConfig(ns, `
+apiVersion: networking.x.k8s.io/v1alpha1
+kind: GatewayClass
+metadata:
+  name: istio
+spec:
+  controller: istio.io/gateway-controller
+---
+apiVersion: networking.x.k8s.io/v1alpha1
+kind: Gateway
+metadata:
+  name: gateway
+spec:
+  class: istio
+  listeners:
+  - name: primary
+    address: {type: NamedAddress, value: crossnetwork.example}
+    port: 80
+    protocol: http
+  routes:
+  - group: networking.x-k8s.io/v1alpha1
+    resource: HTTPRoute
+    name: http
+---
+apiVersion: networking.x.k8s.io/v1alpha1
+kind: HTTPRoute
+metadata:
+  name: http
+spec:
+  hosts:
+  - hostname: "crossnetwork.example"
+    rules:
+    - match: {pathType: Prefix, path: /get}
+      action: {forwardTo: {group: v1, resource: Service, name: server}}`,
+			); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := retry.UntilSuccess(func() error {
+				resp, err := crossNetworkIngress.Call(ingress.CallOptions{
+					Host:     "crossnetwork.example",
+					Path:     "/get",
+					CallType: ingress.PlainText,
+					Address:  crossNetworkIngress.HTTPAddress(),
+				})
+				if err != nil {
+					return err
+				}
+				if resp.Code != 200 {
+					return fmt.Errorf("got invalid response code %v: %v", resp.Code, resp.Body)
+				}
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+		})
+}